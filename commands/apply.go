@@ -2,9 +2,16 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+
+	"github.com/github/hub/git"
+	"github.com/github/hub/github"
+	"github.com/github/hub/log"
 )
 
 var cmdApply = &Command{
@@ -17,6 +24,15 @@ applies that patch from disk with git am or git apply. Similar to
 cherry-pick, but doesn't add new remotes. git am creates commits while
 preserving authorship info while <code>apply</code> only applies the
 patch to the working copy.
+
+GITHUB-URL can also point at a branch ("/tree/<ref>") or a comparison
+("/compare/<base>...<head>"), or be the shortcut "<owner>/<repo>@<ref>",
+where <ref> may be a branch, a tag, or a commit SHA.
+
+## Options:
+	--lfs
+		Check the patch for Git LFS pointer blobs and, if any are found,
+		fetch and check out the real objects after applying it.
 `,
 }
 
@@ -38,50 +54,255 @@ func init() {
   > git apply /tmp/gist-8da7fb575debd88c54cf.txt
 */
 func apply(command *Command, args *Args) {
+	lfs := parseApplyLFSFlag(args)
 	if !args.IsParamsEmpty() {
-		transformApplyArgs(args)
+		transformApplyArgs(args, lfs)
 	}
 }
 
-func transformApplyArgs(args *Args) {
-	urlRegexp := regexp.MustCompile("^https?://(gist\\.)?github\\.com/")
+func parseApplyLFSFlag(args *Args) bool {
+	if i := args.IndexOfParam("--lfs"); i != -1 {
+		args.RemoveParam(i)
+		return true
+	}
+
+	return false
+}
+
+var (
+	applyURLRegexp      = regexp.MustCompile("^https?://(gist\\.)?github\\.com/")
+	applyTreeRegexp     = regexp.MustCompile(`^https?://github\.com/([^/]+)/([^/]+)/tree/(.+)$`)
+	applyCompareRegexp  = regexp.MustCompile(`^https?://github\.com/([^/]+)/([^/]+)/compare/(.+)$`)
+	applyShortcutRegexp = regexp.MustCompile(`^([^/\s@]+)/([^/\s@]+)@(.+)$`)
+)
+
+func transformApplyArgs(args *Args, lfs bool) {
 	for _, url := range args.Params {
-		if urlRegexp.MatchString(url) {
-			idx := args.IndexOfParam(url)
-			gist := urlRegexp.FindStringSubmatch(url)[1] == "gist."
+		idx := args.IndexOfParam(url)
 
-			fragmentRegexp := regexp.MustCompile("#.+")
-			url = fragmentRegexp.ReplaceAllString(url, "")
-			pullRegexp := regexp.MustCompile("(/pull/\\d+)/\\w*$")
-			if !gist {
-				if pullRegexp.MatchString(url) {
-					pull := pullRegexp.FindStringSubmatch(url)[1]
-					url = pullRegexp.ReplaceAllString(url, pull)
-				}
-			}
+		if m := applyShortcutRegexp.FindStringSubmatch(url); m != nil {
+			patchURL, err := resolveRefPatchURL(m[1], m[2], m[3])
+			log.Check(err)
+			applyPatch(args, idx, patchURL, false, lfs)
+			break
+		}
 
-			var ext string
-			if gist {
-				ext = ".txt"
-			} else {
-				ext = ".patch"
+		if !applyURLRegexp.MatchString(url) {
+			continue
+		}
+
+		gist := applyURLRegexp.FindStringSubmatch(url)[1] == "gist."
+
+		fragmentRegexp := regexp.MustCompile("#.+")
+		url = fragmentRegexp.ReplaceAllString(url, "")
+
+		if !gist {
+			if m := applyTreeRegexp.FindStringSubmatch(url); m != nil {
+				patchURL, err := resolveTreeRefPatchURL(m[1], m[2], m[3])
+				log.Check(err)
+				applyPatch(args, idx, patchURL, false, lfs)
+				break
 			}
 
-			if filepath.Ext(url) != ext {
-				url += ext
+			if m := applyCompareRegexp.FindStringSubmatch(url); m != nil {
+				patchURL, err := resolveComparePatchURL(m[1], m[2], m[3])
+				log.Check(err)
+				applyPatch(args, idx, patchURL, false, lfs)
+				break
 			}
 
-			var prefix string
-			if gist {
-				prefix = "gist-"
+			pullRegexp := regexp.MustCompile("(/pull/\\d+)/\\w*$")
+			if pullRegexp.MatchString(url) {
+				pull := pullRegexp.FindStringSubmatch(url)[1]
+				url = pullRegexp.ReplaceAllString(url, pull)
 			}
+		}
 
-			patchFile := filepath.Join(os.TempDir(), prefix+filepath.Base(url))
+		var ext string
+		if gist {
+			ext = ".txt"
+		} else {
+			ext = ".patch"
+		}
 
-			args.Before("curl", "-#LA", fmt.Sprintf("gh %s", Version), url, "-o", patchFile)
-			args.Params[idx] = patchFile
+		if filepath.Ext(url) != ext {
+			url += ext
+		}
 
-			break
+		applyPatch(args, idx, url, gist, lfs)
+		break
+	}
+}
+
+// applyPatch queues the download of patchURL into a temp file and swaps
+// the original GITHUB-URL argument for that file, optionally queuing an
+// LFS follow-up once the patch is applied.
+func applyPatch(args *Args, idx int, patchURL string, gist, lfs bool) {
+	var prefix string
+	if gist {
+		prefix = "gist-"
+	}
+
+	patchFile := filepath.Join(os.TempDir(), prefix+filepath.Base(patchURL))
+
+	args.Before("curl", "-#LA", fmt.Sprintf("gh %s", Version), patchURL, "-o", patchFile)
+	args.Params[idx] = patchFile
+
+	if lfs && !gist {
+		queueLFSFollowUp(args, patchURL)
+	}
+}
+
+// resolveTreeRefPatchURL resolves a "/tree/<ref>" tail against
+// owner/name, where tail may itself contain slashes both because the
+// ref does (e.g. "feature/foo") and because GitHub's /tree/ URLs also
+// allow a path within that ref (e.g. "/tree/main/docs"). It tries the
+// longest possible ref first, then progressively shorter prefixes, so a
+// slash-containing branch name always wins over a same-named shorter
+// branch plus a path underneath it.
+func resolveTreeRefPatchURL(owner, name, tail string) (string, error) {
+	segments := strings.Split(tail, "/")
+
+	var err error
+	for i := len(segments); i >= 1; i-- {
+		var patchURL string
+		patchURL, err = resolveRefPatchURL(owner, name, strings.Join(segments[:i], "/"))
+		if err == nil {
+			return patchURL, nil
+		}
+	}
+
+	return "", err
+}
+
+// resolveRefPatchURL classifies ref as a tag, branch, or commit SHA and
+// returns the .patch URL that captures its content: a tag or SHA maps
+// straight to its commit, while a branch is diffed against the
+// repository's default branch. When the current directory is a clone of
+// owner/name and ref names a local tag, the tag is resolved locally via
+// the typed Ref abstraction, saving an API round-trip; every other ref
+// (a branch, a SHA, or any ref outside such a clone) is classified by
+// the GitHub API.
+func resolveRefPatchURL(owner, name, ref string) (string, error) {
+	if sha, ok := localTagSha(owner, name, ref); ok {
+		log.Debug("git", log.Fields{"ref": ref, "source": "local", "status": "tag"})
+		return fmt.Sprintf("https://github.com/%s/%s/commit/%s.patch", owner, name, sha), nil
+	}
+
+	return remoteRefPatchURL(owner, name, ref)
+}
+
+// localTagSha resolves ref against the local repository's own refs when
+// the current working directory is a clone of owner/name, using
+// ParseRef to tell a tag from a same-named branch, and returns the sha
+// of the commit the tag points at. It reports ok=false whenever local
+// resolution isn't possible or safe, leaving the caller to fall back to
+// the API.
+func localTagSha(owner, name, ref string) (sha string, ok bool) {
+	localRepo, err := github.LocalRepo()
+	if err != nil {
+		return "", false
+	}
+
+	project, err := localRepo.MainProject()
+	if err != nil || !strings.EqualFold(project.Owner, owner) || !strings.EqualFold(project.Name, name) {
+		return "", false
+	}
+
+	parsed, err := git.ParseRef(ref)
+	if err != nil || parsed.Type != git.LocalTag {
+		return "", false
+	}
+
+	sha, err = git.RevParse(parsed.Prefix() + "/" + parsed.Name + "^{commit}")
+	if err != nil {
+		return "", false
+	}
+
+	return sha, true
+}
+
+// resolveComparePatchURL turns a "/compare/<base>...<head>" URL into
+// github.com's ".patch" URL for the same comparison, so git am can
+// apply it exactly like any other patch in this file. It only builds
+// that URL; the GitHub compare API is not called.
+func resolveComparePatchURL(owner, name, rangeSpec string) (string, error) {
+	path := fmt.Sprintf("repos/%s/%s/compare/%s", owner, name, rangeSpec)
+	log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "compare"})
+
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s.patch", owner, name, rangeSpec), nil
+}
+
+// remoteRefPatchURL classifies ref as a tag, branch, or commit SHA via
+// the GitHub API and returns the .patch URL that captures its content:
+// a tag or SHA maps straight to its commit, while a branch is diffed
+// against the repository's default branch.
+func remoteRefPatchURL(owner, name, ref string) (string, error) {
+	project := github.NewProject(owner, name, "")
+	gh := github.NewClient(project.Host)
+	path := fmt.Sprintf("repos/%s/%s/git/refs/%s", owner, name, ref)
+
+	if tag, err := gh.Tag(project, ref); err == nil {
+		log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "tag"})
+		return fmt.Sprintf("https://github.com/%s/%s/commit/%s.patch", owner, name, tag.Commit.Sha), nil
+	}
+
+	if branch, err := gh.Branch(project, ref); err == nil {
+		log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "branch"})
+		repo, err := gh.Repository(project)
+		base := "main"
+		if err == nil && repo.DefaultBranch != "" {
+			base = repo.DefaultBranch
 		}
+		return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s.patch", owner, name, base, branch.Name), nil
+	}
+
+	if commit, err := gh.Commit(project, ref); err == nil {
+		log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "commit"})
+		return fmt.Sprintf("https://github.com/%s/%s/commit/%s.patch", owner, name, commit.Sha), nil
+	}
+
+	log.Warn("github", log.Fields{"method": "GET", "path": path, "status": "unresolved"})
+	return "", fmt.Errorf("Unable to resolve %s in %s/%s as a tag, branch, or commit", ref, owner, name)
+}
+
+var applyOwnerNameRegexp = regexp.MustCompile(`^https?://github\.com/([^/]+)/([^/]+)/`)
+
+// queueLFSFollowUp peeks at the patch independently of the curl download
+// queued above, and, if it touches any LFS pointer blobs, queues a
+// fetch/checkout pass to run after git am/apply has applied it.
+//
+// The fetch/checkout can't run synchronously here via git.LFSFetch /
+// git.LFSCheckout: transformApplyArgs only builds up the command line
+// that the shell trampoline runs later, so the patch hasn't actually
+// been applied yet at this point. Instead this queues the same
+// invocation git.LFSFetch would make, but explicitly against the
+// source repo's clone URL (derived from the original GitHub URL)
+// rather than whatever remote happens to be configured in the CWD.
+func queueLFSFollowUp(args *Args, url string) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return
 	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if len(git.LFSPointerPaths(string(body))) == 0 {
+		return
+	}
+
+	m := applyOwnerNameRegexp.FindStringSubmatch(url)
+	if m == nil {
+		return
+	}
+	owner, name := m[1], m[2]
+	project := github.NewProject(owner, name, "")
+	cloneURL := project.GitURL(name, owner, false)
+
+	args.After("git", "lfs", "fetch", cloneURL)
+	args.After("git", "lfs", "checkout")
 }
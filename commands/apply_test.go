@@ -0,0 +1,35 @@
+package commands
+
+import "testing"
+
+func TestApplyTreeRegexp_capturesOwnerNameAndTail(t *testing.T) {
+	m := applyTreeRegexp.FindStringSubmatch("https://github.com/octocat/hello-world/tree/main/docs")
+	if m == nil {
+		t.Fatal("expected applyTreeRegexp to match a /tree/ URL")
+	}
+	if m[1] != "octocat" || m[2] != "hello-world" || m[3] != "main/docs" {
+		t.Errorf("expected (octocat, hello-world, main/docs), got (%s, %s, %s)", m[1], m[2], m[3])
+	}
+}
+
+func TestApplyCompareRegexp_capturesOwnerNameAndRange(t *testing.T) {
+	m := applyCompareRegexp.FindStringSubmatch("https://github.com/octocat/hello-world/compare/main...feature")
+	if m == nil {
+		t.Fatal("expected applyCompareRegexp to match a /compare/ URL")
+	}
+	if m[1] != "octocat" || m[2] != "hello-world" || m[3] != "main...feature" {
+		t.Errorf("expected (octocat, hello-world, main...feature), got (%s, %s, %s)", m[1], m[2], m[3])
+	}
+}
+
+func TestResolveComparePatchURL_buildsComparePatchURL(t *testing.T) {
+	patchURL, err := resolveComparePatchURL("octocat", "hello-world", "main...feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "https://github.com/octocat/hello-world/compare/main...feature.patch"
+	if patchURL != want {
+		t.Errorf("expected %q, got %q", want, patchURL)
+	}
+}
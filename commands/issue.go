@@ -3,7 +3,8 @@ package commands
 import (
 	"fmt"
 	"github.com/jingweno/gh/github"
-	"github.com/jingweno/gh/utils"
+
+	"github.com/github/hub/log"
 )
 
 var (
@@ -51,8 +52,14 @@ func issue(cmd *Command, args *Args) {
 		if args.Noop {
 			fmt.Printf("Would request list of issues for %s\n", project)
 		} else {
+			path := fmt.Sprintf("repos/%s/issues", project)
 			issues, err := gh.Issues(project)
-			utils.Check(err)
+			if err != nil {
+				log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "error"})
+			} else {
+				log.Debug("github", log.Fields{"method": "GET", "path": path, "status": "ok", "count": len(issues)})
+			}
+			log.Check(err)
 			for _, issue := range issues {
 				var url string
 				// use the pull request URL if we have one
@@ -74,15 +81,21 @@ func createIssue(cmd *Command, args *Args) {
 			fmt.Printf("Would create an issue for %s\n", project)
 		} else {
 			title, body, err := getTitleAndBodyFromFlags(flagIssueMessage, flagIssueFile)
-			utils.Check(err)
+			log.Check(err)
 
 			if title == "" {
 				title, body, err = writeIssueTitleAndBody(project)
-				utils.Check(err)
+				log.Check(err)
 			}
 
+			path := fmt.Sprintf("repos/%s/issues", project)
 			issue, err := gh.CreateIssue(project, title, body, flagIssueLabels)
-			utils.Check(err)
+			if err != nil {
+				log.Debug("github", log.Fields{"method": "POST", "path": path, "status": "error"})
+			} else {
+				log.Debug("github", log.Fields{"method": "POST", "path": path, "status": "created"})
+			}
+			log.Check(err)
 
 			fmt.Println(issue.HTMLURL)
 		}
@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/github/hub/git"
+	"github.com/github/hub/github"
+	"github.com/github/hub/utils"
+)
+
+var cmdMirror = &Command{
+	Run:   mirror,
+	Usage: "mirror <USER-OR-ORG> [--dest <DIR>] [--bare] [--structured] [--lfs] [--include-forks] [--include-archived] [--keep <N>] [--concurrency <N>] [--zip]",
+	Short: "Clone every repository owned by a user or organization",
+	Long: `Enumerates the repositories owned by USER-OR-ORG via the GitHub API and
+clones each one, which is handy for keeping a local backup or mirror.
+
+## Options:
+	--dest <DIR>
+		Directory to clone into. Defaults to the current directory.
+
+	--bare
+		Make a bare mirror clone ("git clone --mirror") instead of a working copy.
+
+	--structured
+		Clone into <DEST>/<HOST>/<OWNER>/<REPO> instead of <DEST>/<REPO>.
+
+	--lfs
+		Fetch Git LFS objects for each repository after cloning.
+
+	--include-forks
+		Also clone repositories that are forks.
+
+	--include-archived
+		Also clone repositories that are archived.
+
+	--keep <N>
+		Append a timestamp to each clone's directory name and prune older
+		snapshots of the same repository so only the N most recent remain.
+
+	--concurrency <N>
+		Clone up to N repositories at once. Defaults to 1.
+
+	--zip
+		Archive each snapshot into a .zip file after cloning.
+`,
+}
+
+var (
+	flagMirrorDest                                     string
+	flagMirrorBare, flagMirrorStructured, flagMirrorLFS bool
+	flagMirrorIncludeForks, flagMirrorIncludeArchived   bool
+	flagMirrorZip                                       bool
+	flagMirrorKeep, flagMirrorConcurrency               int
+)
+
+func init() {
+	cmdMirror.Flag.StringVar(&flagMirrorDest, "dest", ".", "DIR")
+	cmdMirror.Flag.BoolVar(&flagMirrorBare, "bare", false, "")
+	cmdMirror.Flag.BoolVar(&flagMirrorStructured, "structured", false, "")
+	cmdMirror.Flag.BoolVar(&flagMirrorLFS, "lfs", false, "")
+	cmdMirror.Flag.BoolVar(&flagMirrorIncludeForks, "include-forks", false, "")
+	cmdMirror.Flag.BoolVar(&flagMirrorIncludeArchived, "include-archived", false, "")
+	cmdMirror.Flag.BoolVar(&flagMirrorZip, "zip", false, "")
+	cmdMirror.Flag.IntVar(&flagMirrorKeep, "keep", 0, "N")
+	cmdMirror.Flag.IntVar(&flagMirrorConcurrency, "concurrency", 1, "N")
+
+	CmdRunner.Use(cmdMirror)
+}
+
+func mirror(cmd *Command, args *Args) {
+	if args.IsParamsEmpty() {
+		utils.Check(fmt.Errorf("Usage: hub mirror <USER-OR-ORG>"))
+	}
+	owner := args.FirstParam()
+
+	hostConfig, err := github.CurrentConfig().DefaultHost()
+	utils.Check(err)
+
+	gh := github.NewClient(hostConfig.Host)
+	repos, err := gh.Repositories(owner)
+	utils.Check(err)
+
+	if args.Noop {
+		for _, repo := range repos {
+			if repo.Fork && !flagMirrorIncludeForks {
+				continue
+			}
+			if repo.Archived && !flagMirrorIncludeArchived {
+				continue
+			}
+			fmt.Printf("Would mirror %s\n", repo.FullName)
+		}
+		return
+	}
+
+	concurrency := flagMirrorConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, repo := range repos {
+		if repo.Fork && !flagMirrorIncludeForks {
+			continue
+		}
+		if repo.Archived && !flagMirrorIncludeArchived {
+			continue
+		}
+
+		repo := repo
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := mirrorRepository(hostConfig.Host, owner, repo); err != nil {
+				utils.Check(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func mirrorRepository(host, owner string, repo github.Repository) error {
+	dest := repoDestDir(host, owner, repo.Name)
+	if flagMirrorKeep > 0 {
+		dest = fmt.Sprintf("%s-%s", dest, time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	clone := git.NewCommand("clone")
+	if flagMirrorBare {
+		clone.AddOptions("--mirror")
+	}
+	if err := clone.AddDynamicArguments(repo.CloneURL, dest).Run(); err != nil {
+		return fmt.Errorf("Can't clone %s: %s", repo.FullName, err)
+	}
+
+	if err := maybeFetchLFS(dest); err != nil {
+		return err
+	}
+
+	if flagMirrorKeep > 0 {
+		if err := pruneSnapshots(repoDestDir(host, owner, repo.Name), flagMirrorKeep); err != nil {
+			return err
+		}
+	}
+
+	if flagMirrorZip {
+		if err := zipDirectory(dest, dest+".zip"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maybeFetchLFS fetches LFS objects into dest when --lfs was requested
+// and the git-lfs extension is available, keeping the LFS support
+// surface (git.LFSInstalled / git.LFSFetchAllIn, both in git/lfs.go)
+// isolated behind a single call site.
+func maybeFetchLFS(dest string) error {
+	if !flagMirrorLFS || !git.LFSInstalled() {
+		return nil
+	}
+
+	return git.LFSFetchAllIn(dest)
+}
+
+func repoDestDir(host, owner, name string) string {
+	if flagMirrorStructured {
+		return filepath.Join(flagMirrorDest, host, owner, name)
+	}
+
+	return filepath.Join(flagMirrorDest, name)
+}
+
+// pruneSnapshots removes the oldest "<base>-<timestamp>" directories,
+// along with their .zip archives, so that only the `keep` most recent
+// ones remain. Entries whose suffix is not such a timestamp (another
+// repository's snapshots, the archives themselves) are not counted.
+func pruneSnapshots(base string, keep int) error {
+	matches, err := filepath.Glob(base + "-*")
+	if err != nil {
+		return err
+	}
+
+	var snapshots []string
+	for _, m := range matches {
+		suffix := filepath.Base(m)[len(filepath.Base(base))+1:]
+		if _, err := time.Parse("20060102T150405Z", suffix); err == nil {
+			snapshots = append(snapshots, m)
+		}
+	}
+
+	sort.Strings(snapshots)
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	for _, stale := range snapshots[:len(snapshots)-keep] {
+		if err := os.RemoveAll(stale); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(stale + ".zip"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func zipDirectory(src, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w := zip.NewWriter(out)
+	defer w.Close()
+
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		entry, err := w.Create(rel)
+		if err != nil {
+			return err
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		_, err = io.Copy(entry, in)
+		return err
+	})
+}
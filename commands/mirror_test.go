@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoDestDir_flat(t *testing.T) {
+	flagMirrorDest = "/dest"
+	flagMirrorStructured = false
+
+	got := repoDestDir("github.com", "octocat", "hello-world")
+	want := filepath.Join("/dest", "hello-world")
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRepoDestDir_structured(t *testing.T) {
+	flagMirrorDest = "/dest"
+	flagMirrorStructured = true
+	defer func() { flagMirrorStructured = false }()
+
+	got := repoDestDir("github.com", "octocat", "hello-world")
+	want := filepath.Join("/dest", "github.com", "octocat", "hello-world")
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPruneSnapshots_keepsOnlyTheNewestN(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror-prune")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := filepath.Join(dir, "hello-world")
+	snapshots := []string{
+		base + "-20240101T000000Z",
+		base + "-20240102T000000Z",
+		base + "-20240103T000000Z",
+	}
+	for _, s := range snapshots {
+		if err := os.Mkdir(s, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pruneSnapshots(base, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(snapshots[0]); !os.IsNotExist(err) {
+		t.Errorf("expected oldest snapshot %q to be pruned", snapshots[0])
+	}
+	for _, s := range snapshots[1:] {
+		if _, err := os.Stat(s); err != nil {
+			t.Errorf("expected snapshot %q to survive pruning, got %v", s, err)
+		}
+	}
+}
+
+func TestZipDirectory_roundTrips(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror-zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "repo")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "sub", "file.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(dir, "repo.zip")
+	if err := zipDirectory(src, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() == 0 {
+		t.Error("expected a non-empty zip file")
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/github/hub/git"
 	"github.com/github/hub/github"
-	"github.com/github/hub/utils"
+	"github.com/github/hub/log"
 )
 
 var cmdRemote = &Command{
@@ -60,7 +60,10 @@ func transformRemoteArgs(args *Args) {
 	}
 
 	localRepo, err := github.LocalRepo()
-	utils.Check(err)
+	if err != nil {
+		log.Debug("git", log.Fields{"method": "LocalRepo", "status": "error"})
+	}
+	log.Check(err)
 
 	var repoName, host string
 	if name == "" {
@@ -70,7 +73,7 @@ func transformRemoteArgs(args *Args) {
 			host = project.Host
 		} else {
 			repoName, err = git.RootDirName()
-			utils.Check(err)
+			log.Check(err)
 		}
 
 		name = repoName
@@ -78,7 +81,8 @@ func transformRemoteArgs(args *Args) {
 
 	hostConfig, err := github.CurrentConfig().DefaultHost()
 	if err != nil {
-		utils.Check(github.FormatError("adding remote", err))
+		log.Debug("github", log.Fields{"method": "DefaultHost", "status": "error"})
+		log.Check(github.FormatError("adding remote", err))
 	}
 
 	words := args.Words()
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/hub/cmd"
+	"github.com/github/hub/log"
+)
+
+// gitCommandOptions is the whitelist of literal flags that AddOptions
+// will accept, in addition to any "--format=..." flag. Anything else
+// must go through AddDynamicArguments, which
+// refuses values that could be mistaken for options. This keeps a
+// user-controlled ref or path from ever being interpreted by git as a
+// flag (e.g. a branch named "--upload-pack=evil").
+var gitCommandOptions = map[string]bool{
+	"--":                   true,
+	"-q":                   true,
+	"-s":                   true,
+	"-v":                   true,
+	"--all":                true,
+	"--global":             true,
+	"--symbolic-full-name": true,
+	"--cherry-pick":        true,
+	"--right-only":         true,
+	"--no-merges":          true,
+	"--no-color":           true,
+	"--cherry":             true,
+	"--mirror":             true,
+}
+
+// Command builds up a `git` invocation with a clear split between
+// trusted, literal options and dynamic, user-controlled arguments such
+// as ref names or paths.
+type Command struct {
+	*cmd.Cmd
+	err error
+}
+
+// NewCommand starts building `git <name...>`.
+func NewCommand(name ...string) *Command {
+	c := cmd.New("git")
+	for _, v := range GlobalFlags {
+		c.WithArg(v)
+	}
+	for _, n := range name {
+		c.WithArg(n)
+	}
+
+	return &Command{Cmd: c}
+}
+
+// NewCommandIn is like NewCommand, but runs git as though it had been
+// invoked from dir (via "-C") instead of relying on the process's
+// current working directory. Prefer this over os.Chdir when a command
+// needs to target a directory other than the process's own, since
+// os.Chdir mutates process-wide state and races other goroutines doing
+// the same (see hub mirror's per-repository, concurrency-limited
+// clones).
+func NewCommandIn(dir string, name ...string) *Command {
+	c := cmd.New("git")
+	for _, v := range GlobalFlags {
+		c.WithArg(v)
+	}
+	c.WithArg("-C")
+	c.WithArg(dir)
+	for _, n := range name {
+		c.WithArg(n)
+	}
+
+	return &Command{Cmd: c}
+}
+
+// AddOptions appends one or more whitelisted literal flags. Values that
+// aren't recognized are rejected rather than silently passed through --
+// callers that need to pass along user-controlled data should use
+// AddDynamicArguments instead.
+func (c *Command) AddOptions(opts ...string) *Command {
+	for _, opt := range opts {
+		if !gitCommandOptions[opt] && !strings.HasPrefix(opt, "--format=") {
+			if c.err == nil {
+				c.err = fmt.Errorf("git: %q is not a recognized option", opt)
+			}
+			continue
+		}
+
+		c.WithArg(opt)
+	}
+
+	return c
+}
+
+// AddDynamicArguments appends user-controlled values such as ref names
+// or paths. Any value that starts with "-" is rejected outright, since
+// git would otherwise interpret it as an option no matter what it's
+// meant to represent.
+//
+// Unlike a pathspec, a "--" separator is NOT inserted automatically:
+// for revision-only commands (rev-parse, show, log, rev-list, ...) a
+// "--" actually changes the command's meaning ("everything after this
+// is a path, not a revision"), so only callers that really are passing
+// paths should add one explicitly via AddOptions("--") first.
+func (c *Command) AddDynamicArguments(args ...string) *Command {
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			if c.err == nil {
+				c.err = fmt.Errorf("arg %q is ambiguous with a git option", a)
+			}
+			continue
+		}
+		c.WithArg(a)
+	}
+
+	return c
+}
+
+// Err returns the first error recorded while building the command, if
+// any, without running it.
+func (c *Command) Err() error {
+	return c.err
+}
+
+func (c *Command) CombinedOutput() (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	start := time.Now()
+	out, err := c.Cmd.CombinedOutput()
+	log.Debug("git", log.Fields{"argv": c.Cmd.Args, "duration": time.Since(start).String()})
+
+	return out, err
+}
+
+func (c *Command) Run() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	start := time.Now()
+	err := c.Cmd.Run()
+	log.Debug("git", log.Fields{"argv": c.Cmd.Args, "duration": time.Since(start).String()})
+
+	return err
+}
+
+// OutputLines runs the command and splits its combined output into
+// non-empty, trimmed lines, mirroring the behavior of gitOutput.
+func (c *Command) OutputLines() (outputs []string, err error) {
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			outputs = append(outputs, line)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,39 @@
+package git
+
+import "testing"
+
+func TestAddDynamicArguments_rejectsOptionLookingValues(t *testing.T) {
+	malicious := []string{
+		"--upload-pack=id",
+		"-oProxyCommand=id",
+		"--",
+	}
+
+	for _, arg := range malicious {
+		c := NewCommand("rev-parse").AddOptions("-q").AddDynamicArguments(arg)
+		if c.Err() == nil {
+			t.Errorf("expected %q to be rejected as a dynamic argument", arg)
+		}
+	}
+}
+
+func TestRevParse_rejectsMaliciousBranchName(t *testing.T) {
+	_, err := RevParse("--upload-pack=id")
+	if err == nil {
+		t.Error("expected RevParse to reject a branch name that looks like an option")
+	}
+}
+
+func TestRefList_rejectsMaliciousBranchName(t *testing.T) {
+	_, err := RefList("--upload-pack=id", "master")
+	if err == nil {
+		t.Error("expected RefList to reject a branch name that looks like an option")
+	}
+}
+
+func TestAddOptions_rejectsUnknownFlag(t *testing.T) {
+	c := NewCommand("log").AddOptions("--exec=id")
+	if c.Err() == nil {
+		t.Error("expected AddOptions to reject a flag outside the whitelist")
+	}
+}
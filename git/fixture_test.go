@@ -0,0 +1,62 @@
+package git
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/github/hub/fixtures"
+)
+
+var shaRegexp = regexp.MustCompile(`^[0-9a-f]{4,40}$`)
+
+func TestRevParse_resolvesRealRef(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	sha, err := RevParse("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !shaRegexp.MatchString(sha) {
+		t.Errorf("expected HEAD to resolve to a sha, got %q", sha)
+	}
+}
+
+func TestShow_describesRealCommit(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	out, err := Show("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == "" {
+		t.Error("expected Show(HEAD) to return a non-empty commit summary")
+	}
+}
+
+func TestLog_describesCommitRangeAgainstItself(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	out, err := Log("HEAD", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "" {
+		t.Errorf("expected no commits between HEAD and itself, got %q", out)
+	}
+}
+
+func TestRefList_onRealRepo(t *testing.T) {
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	list, err := RefList("HEAD", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no revisions between HEAD and itself, got %v", list)
+	}
+}
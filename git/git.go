@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/github/hub/cmd"
+	"github.com/github/hub/log"
 )
 
 const AuthorSignatureHeader = "Signed-off-by: "
@@ -95,17 +97,18 @@ func Head() (string, error) {
 }
 
 func SymbolicFullName(name string) (string, error) {
-	output, err := gitOutput("rev-parse", "--symbolic-full-name", name)
-	if err != nil {
+	output, err := NewCommand("rev-parse").AddOptions("--symbolic-full-name").AddDynamicArguments(name).OutputLines()
+	if err != nil || len(output) == 0 {
 		return "", fmt.Errorf("Unknown revision or path not in the working tree: %s", name)
 	}
 
 	return output[0], nil
 }
 
-func Ref(ref string) (string, error) {
-	output, err := gitOutput("rev-parse", "-q", ref)
-	if err != nil {
+// RevParse resolves ref to the sha1 it currently points at.
+func RevParse(ref string) (string, error) {
+	output, err := NewCommand("rev-parse").AddOptions("-q").AddDynamicArguments(ref).OutputLines()
+	if err != nil || len(output) == 0 {
 		return "", fmt.Errorf("Unknown revision or path not in the working tree: %s", ref)
 	}
 
@@ -114,7 +117,7 @@ func Ref(ref string) (string, error) {
 
 func RefList(a, b string) ([]string, error) {
 	ref := fmt.Sprintf("%s...%s", a, b)
-	output, err := gitOutput("rev-list", "--cherry-pick", "--right-only", "--no-merges", ref)
+	output, err := NewCommand("rev-list").AddOptions("--cherry-pick", "--right-only", "--no-merges").AddDynamicArguments(ref).OutputLines()
 	if err != nil {
 		return []string{}, fmt.Errorf("Can't load rev-list for %s", ref)
 	}
@@ -132,24 +135,18 @@ func CommentChar() string {
 }
 
 func Show(sha string) (string, error) {
-	cmd := cmd.New("git")
-	cmd.WithArg("show").WithArg("-s").WithArg("--format=%s%n%+b").WithArg(sha)
-
-	output, err := cmd.CombinedOutput()
+	output, err := NewCommand("show").AddOptions("-s", "--format=%s%n%+b").AddDynamicArguments(sha).CombinedOutput()
 	output = strings.TrimSpace(output)
 
 	return output, err
 }
 
 func Log(sha1, sha2 string) (string, error) {
-	execCmd := cmd.New("git")
-	execCmd.WithArg("log").WithArg("--no-color")
-	execCmd.WithArg("--format=%h (%aN, %ar)%n%w(78,3,3)%s%n%+b")
-	execCmd.WithArg("--cherry")
 	shaRange := fmt.Sprintf("%s...%s", sha1, sha2)
-	execCmd.WithArg(shaRange)
-
-	outputs, err := execCmd.CombinedOutput()
+	outputs, err := NewCommand("log").
+		AddOptions("--no-color", "--format=%h (%aN, %ar)%n%w(78,3,3)%s%n%+b", "--cherry").
+		AddDynamicArguments(shaRange).
+		CombinedOutput()
 	if err != nil {
 		return "", fmt.Errorf("Can't load git log %s..%s", sha1, sha2)
 	}
@@ -158,7 +155,7 @@ func Log(sha1, sha2 string) (string, error) {
 }
 
 func Remotes() ([]string, error) {
-	return gitOutput("remote", "-v")
+	return NewCommand("remote").AddOptions("-v").OutputLines()
 }
 
 func Config(name string) (string, error) {
@@ -209,8 +206,8 @@ func SetGlobalConfig(name, value string) error {
 }
 
 func gitGetConfig(args ...string) (string, error) {
-	output, err := gitOutput(gitConfigCommand(args)...)
-	if err != nil {
+	output, err := gitConfigCommand(args).OutputLines()
+	if err != nil || len(output) == 0 {
 		return "", fmt.Errorf("Unknown config %s", args[len(args)-1])
 	}
 
@@ -218,12 +215,17 @@ func gitGetConfig(args ...string) (string, error) {
 }
 
 func gitConfig(args ...string) ([]string, error) {
-	return gitOutput(gitConfigCommand(args)...)
+	return gitConfigCommand(args).OutputLines()
 }
 
-func gitConfigCommand(args []string) []string {
-	cmd := []string{"config"}
-	return append(cmd, args...)
+func gitConfigCommand(args []string) *Command {
+	c := NewCommand("config")
+	if len(args) > 0 && args[0] == "--global" {
+		c.AddOptions("--global")
+		args = args[1:]
+	}
+
+	return c.AddDynamicArguments(args...)
 }
 
 func Alias(name string) (string, error) {
@@ -272,7 +274,11 @@ func Run(command string, args ...string) error {
 		cmd.WithArg(a)
 	}
 
-	return cmd.Run()
+	start := time.Now()
+	err := cmd.Run()
+	log.Debug("git", log.Fields{"argv": cmd.Args, "duration": time.Since(start).String()})
+
+	return err
 }
 
 func gitOutput(input ...string) (outputs []string, err error) {
@@ -286,7 +292,10 @@ func gitOutput(input ...string) (outputs []string, err error) {
 		cmd.WithArg(i)
 	}
 
+	start := time.Now()
 	out, err := cmd.CombinedOutput()
+	log.Debug("git", log.Fields{"argv": cmd.Args, "duration": time.Since(start).String()})
+
 	for _, line := range strings.Split(out, "\n") {
 		line = strings.TrimSpace(line)
 		if line != "" {
@@ -0,0 +1,73 @@
+package git
+
+import "strings"
+
+// lfsPointerSignature is the first line of every Git LFS pointer file,
+// per https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md
+const lfsPointerSignature = "version https://git-lfs.github.com/spec/v1"
+
+// LFSInstalled reports whether the git-lfs extension is available by
+// probing `git lfs version`.
+func LFSInstalled() bool {
+	_, err := NewCommand("lfs", "version").CombinedOutput()
+	return err == nil
+}
+
+// LFSFetchAll downloads every LFS object referenced by the current
+// repository's refs.
+func LFSFetchAll() error {
+	return NewCommand("lfs", "fetch").AddOptions("--all").Run()
+}
+
+// LFSFetchAllIn is like LFSFetchAll, but targets dir directly via "-C"
+// rather than the current process's working directory, so it's safe to
+// call from multiple goroutines concurrently.
+func LFSFetchAllIn(dir string) error {
+	return NewCommandIn(dir, "lfs", "fetch").AddOptions("--all").Run()
+}
+
+// LFSFetch downloads the LFS objects needed for the given refs from
+// remote, or from the configured default remote when remote is empty.
+func LFSFetch(remote string, refs ...string) error {
+	c := NewCommand("lfs", "fetch")
+	if remote != "" {
+		c.AddDynamicArguments(remote)
+	}
+	if len(refs) > 0 {
+		c.AddDynamicArguments(refs...)
+	}
+
+	return c.Run()
+}
+
+// LFSCheckout replaces LFS pointer files in the working copy with their
+// real blob contents, restricted to paths when given.
+func LFSCheckout(paths ...string) error {
+	c := NewCommand("lfs", "checkout")
+	if len(paths) > 0 {
+		c.AddDynamicArguments(paths...)
+	}
+
+	return c.Run()
+}
+
+// LFSPointerPaths scans a unified diff and returns the paths of every
+// file whose new content is an LFS pointer blob, so callers can decide
+// whether a patch needs a follow-up `git lfs fetch`/`checkout`.
+func LFSPointerPaths(diff string) []string {
+	var paths []string
+	var currentPath string
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ b/") {
+			currentPath = strings.TrimPrefix(line, "+++ b/")
+			continue
+		}
+		if currentPath != "" && strings.Contains(line, lfsPointerSignature) {
+			paths = append(paths, currentPath)
+			currentPath = ""
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/hub/fixtures"
+)
+
+func TestLFSPointerPaths_detectsPointerBlob(t *testing.T) {
+	diff, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "lfs_pointer.patch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := LFSPointerPaths(string(diff))
+	if len(paths) != 1 || paths[0] != "assets/logo.png" {
+		t.Errorf("expected to detect assets/logo.png as an LFS pointer, got %v", paths)
+	}
+}
+
+func TestLFSPointerPaths_ignoresRegularDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,1 +1,1 @@
+-old
++new
+`
+	if paths := LFSPointerPaths(diff); len(paths) != 0 {
+		t.Errorf("expected no LFS pointers, got %v", paths)
+	}
+}
+
+// TestLFSFetchAndCheckout_restoresPointerContent commits a real LFS
+// asset into a fixture repo (so its bytes live in the local LFS object
+// store, the same place LFSFetch would have populated them from a
+// remote), corrupts the working copy back down to a pointer stub, and
+// confirms LFSFetch/LFSCheckout restore the exact original bytes.
+func TestLFSFetchAndCheckout_restoresPointerContent(t *testing.T) {
+	if !LFSInstalled() {
+		t.Skip("git-lfs is not installed")
+	}
+
+	repo := fixtures.SetupTestRepo()
+	defer repo.TearDown()
+
+	if err := NewCommand("lfs", "install", "--local").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCommand("lfs", "track", "*.bin").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte("hello from git-lfs\n")
+	if err := ioutil.WriteFile("asset.bin", want, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCommand("add", ".gitattributes", "asset.bin").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCommand("commit", "-m", "add LFS asset").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a checkout that only received the pointer stub, the way
+	// a fresh clone without `git lfs fetch` would.
+	pointer, err := NewCommand("show", "HEAD:asset.bin").CombinedOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("asset.bin", []byte(pointer), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LFSFetch(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := LFSCheckout("asset.bin"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile("asset.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("expected checkout to restore %q, got %q", want, got)
+	}
+}
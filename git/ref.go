@@ -0,0 +1,130 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RefType classifies what kind of ref a Ref points to.
+type RefType int
+
+const (
+	Other RefType = iota
+	LocalBranch
+	RemoteBranch
+	LocalTag
+	RemoteTag
+	HEAD
+)
+
+// Ref is a single, fully resolved git reference.
+type Ref struct {
+	Name   string
+	Sha    string
+	Type   RefType
+	Remote string
+}
+
+// Prefix returns the refs/ namespace that Ref's Type lives under.
+func (r *Ref) Prefix() string {
+	switch r.Type {
+	case LocalBranch:
+		return "refs/heads"
+	case RemoteBranch:
+		return "refs/remotes"
+	case LocalTag, RemoteTag:
+		return "refs/tags"
+	case HEAD:
+		return "refs"
+	default:
+		return ""
+	}
+}
+
+// ParseRef resolves a short, possibly ambiguous ref name the way git
+// itself would: `rev-parse --symbolic-full-name` finds its full name, a
+// name shared by a branch and a tag resolves to the tag, and
+// `rev-parse` resolves the sha.
+func ParseRef(name string) (*Ref, error) {
+	if name == "HEAD" {
+		sha, err := RevParse("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		return &Ref{Name: "HEAD", Sha: sha, Type: HEAD}, nil
+	}
+
+	full, err := SymbolicFullName(name)
+	if err != nil {
+		return nil, err
+	}
+	// For a name that is both a branch and a tag, rev-parse prints only an
+	// "ambiguous" warning instead of a full name; git resolves it to the tag.
+	if !strings.HasPrefix(full, "refs/") {
+		if tag, err := SymbolicFullName("refs/tags/" + name); err == nil && strings.HasPrefix(tag, "refs/tags/") {
+			full = tag
+		}
+	}
+
+	sha, err := RevParse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return refFromFullName(full, sha), nil
+}
+
+func refFromFullName(full, sha string) *Ref {
+	switch {
+	case strings.HasPrefix(full, "refs/heads/"):
+		return &Ref{Name: strings.TrimPrefix(full, "refs/heads/"), Sha: sha, Type: LocalBranch}
+	case strings.HasPrefix(full, "refs/remotes/"):
+		rest := strings.TrimPrefix(full, "refs/remotes/")
+		parts := strings.SplitN(rest, "/", 2)
+		name := ""
+		if len(parts) == 2 {
+			name = parts[1]
+		}
+		return &Ref{Name: name, Sha: sha, Type: RemoteBranch, Remote: parts[0]}
+	case strings.HasPrefix(full, "refs/tags/"):
+		return &Ref{Name: strings.TrimPrefix(full, "refs/tags/"), Sha: sha, Type: LocalTag}
+	default:
+		return &Ref{Name: full, Sha: sha, Type: Other}
+	}
+}
+
+// LocalBranches lists every local branch.
+func LocalBranches() ([]*Ref, error) {
+	return forEachRef("refs/heads")
+}
+
+// RemoteBranches lists every branch under the given remote.
+func RemoteBranches(remote string) ([]*Ref, error) {
+	return forEachRef(fmt.Sprintf("refs/remotes/%s", remote))
+}
+
+// Tags lists every tag.
+func Tags() ([]*Ref, error) {
+	return forEachRef("refs/tags")
+}
+
+func forEachRef(pattern string) ([]*Ref, error) {
+	lines, err := NewCommand("for-each-ref").
+		AddOptions("--format=%(objectname) %(refname)").
+		AddDynamicArguments(pattern).
+		OutputLines()
+	if err != nil {
+		return nil, fmt.Errorf("Can't list refs under %s", pattern)
+	}
+
+	refs := make([]*Ref, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		refs = append(refs, refFromFullName(fields[1], fields[0]))
+	}
+
+	return refs, nil
+}
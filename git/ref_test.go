@@ -0,0 +1,24 @@
+package git
+
+import "testing"
+
+func TestRefFromFullName_localBranch(t *testing.T) {
+	ref := refFromFullName("refs/heads/master", "abc123")
+	if ref.Type != LocalBranch || ref.Name != "master" || ref.Prefix() != "refs/heads" {
+		t.Errorf("unexpected ref: %+v", ref)
+	}
+}
+
+func TestRefFromFullName_remoteBranch(t *testing.T) {
+	ref := refFromFullName("refs/remotes/origin/master", "abc123")
+	if ref.Type != RemoteBranch || ref.Name != "master" || ref.Remote != "origin" {
+		t.Errorf("unexpected ref: %+v", ref)
+	}
+}
+
+func TestRefFromFullName_tag(t *testing.T) {
+	ref := refFromFullName("refs/tags/v1.0.0", "abc123")
+	if ref.Type != LocalTag || ref.Name != "v1.0.0" || ref.Prefix() != "refs/tags" {
+		t.Errorf("unexpected ref: %+v", ref)
+	}
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func withOutput(t *testing.T, fn func(buf *bytes.Buffer)) {
+	old := Output
+	buf := &bytes.Buffer{}
+	Output = buf
+	defer func() { Output = old }()
+	fn(buf)
+}
+
+func TestError_textModeIsBareMessage(t *testing.T) {
+	os.Unsetenv("HUB_LOG_FORMAT")
+	os.Unsetenv("HUB_LOG_LEVEL")
+
+	withOutput(t, func(buf *bytes.Buffer) {
+		Error("boom", nil)
+		if got := strings.TrimSpace(buf.String()); got != "boom" {
+			t.Errorf("expected bare message, got %q", got)
+		}
+	})
+}
+
+func TestDebug_hiddenByDefaultLevel(t *testing.T) {
+	os.Unsetenv("HUB_LOG_FORMAT")
+	os.Setenv("HUB_LOG_LEVEL", "info")
+	defer os.Unsetenv("HUB_LOG_LEVEL")
+
+	withOutput(t, func(buf *bytes.Buffer) {
+		Debug("should not appear", nil)
+		if buf.Len() != 0 {
+			t.Errorf("expected no output at info level, got %q", buf.String())
+		}
+	})
+}
+
+func TestDebug_jsonModeIncludesFields(t *testing.T) {
+	os.Setenv("HUB_LOG_FORMAT", "json")
+	os.Setenv("HUB_LOG_LEVEL", "debug")
+	defer os.Unsetenv("HUB_LOG_FORMAT")
+	defer os.Unsetenv("HUB_LOG_LEVEL")
+
+	withOutput(t, func(buf *bytes.Buffer) {
+		Debug("git", Fields{"cmd": "git", "argv": []string{"status"}})
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("expected valid JSON, got %q: %s", buf.String(), err)
+		}
+		if record["level"] != "debug" || record["cmd"] != "git" {
+			t.Errorf("unexpected record: %+v", record)
+		}
+	})
+}